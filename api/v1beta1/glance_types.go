@@ -0,0 +1,174 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// KeystoneEndpointType - the Keystone service catalog interface a GlanceAPI
+// service override applies to
+type KeystoneEndpointType string
+
+const (
+	// KSVCPublic - the public Keystone endpoint interface
+	KSVCPublic KeystoneEndpointType = "public"
+	// KSVCInternal - the internal Keystone endpoint interface
+	KSVCInternal KeystoneEndpointType = "internal"
+	// KSVCAdmin - the admin Keystone endpoint interface
+	KSVCAdmin KeystoneEndpointType = "admin"
+)
+
+// AllKeystoneEndpointTypes - the full set of endpoint interfaces Keystone
+// recognizes for a service catalog entry
+var AllKeystoneEndpointTypes = []KeystoneEndpointType{KSVCPublic, KSVCInternal, KSVCAdmin}
+
+// Override - allows the service endpoints that get created to be overridden
+// on a per Keystone endpoint type basis
+type Override struct {
+	// Service - per Keystone endpoint type overrides for the Service/Route
+	// created for this GlanceAPI
+	// +kubebuilder:validation:Optional
+	Service map[KeystoneEndpointType]RoutedOverrideSpec `json:"service,omitempty"`
+}
+
+// RoutedOverrideSpec - overrides applied to the Service/Route generated for
+// a single Keystone endpoint type
+type RoutedOverrideSpec struct {
+	// EndpointURL - if set, overrides the auto-generated endpoint URL
+	// advertised for this interface
+	// +kubebuilder:validation:Optional
+	EndpointURL string `json:"endpointURL,omitempty"`
+}
+
+// Storage - the PVC-backed storage parameters for a GlanceAPI instance
+type Storage struct {
+	// StorageClass - the PVC storage class used to back this GlanceAPI
+	// instance's backend. Empty means the cluster default storage class.
+	// +kubebuilder:validation:Optional
+	StorageClass string `json:"storageClass,omitempty"`
+
+	// AccessMode - the PVC access mode used to back this GlanceAPI instance's
+	// backend
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=ReadWriteOnce
+	AccessMode corev1.PersistentVolumeAccessMode `json:"accessMode,omitempty"`
+}
+
+// GlanceAPITemplate defines the input parameters for a GlanceAPI sub-CR
+// owned by this Glance instance
+type GlanceAPITemplate struct {
+	// ContainerImage - the container image used by this GlanceAPI instance.
+	// When empty, the operator-wide default is used.
+	// +kubebuilder:validation:Optional
+	ContainerImage string `json:"containerImage,omitempty"`
+
+	// Backend - the storage backend this GlanceAPI instance is configured
+	// for. Determines which PVC access modes Storage.AccessMode may use; see
+	// BackendType.IsAccessModeAllowed.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=file
+	Backend BackendType `json:"backend,omitempty"`
+
+	// Type - the deployment layout for this GlanceAPI: "single" runs the API
+	// and backend in one StatefulSet, "split" separates external and
+	// internal API instances. Immutable unless spec.allowLayoutMigration is
+	// set, in which case the controller performs a supervised transition.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=single;split
+	// +kubebuilder:default=single
+	Type string `json:"type,omitempty"`
+
+	// Replicas - the number of GlanceAPI replicas to run
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=1
+	Replicas *int32 `json:"replicas,omitempty"`
+
+	// Storage - the storage backend class and access mode backing this
+	// GlanceAPI instance. Immutable once set.
+	// +kubebuilder:validation:Optional
+	Storage Storage `json:"storage,omitempty"`
+
+	// Override - per Keystone endpoint type overrides for the Service/Route
+	// created for this GlanceAPI
+	// +kubebuilder:validation:Optional
+	Override Override `json:"override,omitempty"`
+}
+
+// GlanceSpec defines the desired state of Glance
+type GlanceSpec struct {
+	// ContainerImage - the default container image used for any GlanceAPI
+	// instance that doesn't set its own
+	// +kubebuilder:validation:Optional
+	ContainerImage string `json:"containerImage,omitempty"`
+
+	// KeystoneEndpoint - the name of the GlanceAPIs map entry that should be
+	// registered in the Keystone service catalog
+	// +kubebuilder:validation:Optional
+	KeystoneEndpoint string `json:"keystoneEndpoint,omitempty"`
+
+	// GlanceAPIs - map of GlanceAPI instances, keyed by instance name, owned
+	// by this Glance
+	// +kubebuilder:validation:Optional
+	GlanceAPIs map[string]GlanceAPITemplate `json:"glanceAPIs,omitempty"`
+
+	// AllowLayoutMigration - opt-in flag that allows a GlanceAPI's Type
+	// (single/split) to be changed after creation. When set, the controller
+	// performs a supervised transition instead of the webhook rejecting the
+	// update outright.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=false
+	AllowLayoutMigration bool `json:"allowLayoutMigration,omitempty"`
+}
+
+// GlanceStatus defines the observed state of Glance
+type GlanceStatus struct {
+	// Conditions - the overall status of the Glance deployment
+	// +kubebuilder:validation:Optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// LayoutMigrationsInProgress - names of the GlanceAPI instances currently
+	// being transitioned between "single" and "split" layout
+	// +kubebuilder:validation:Optional
+	LayoutMigrationsInProgress []string `json:"layoutMigrationsInProgress,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+
+// Glance is the Schema for the glances API
+type Glance struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   GlanceSpec   `json:"spec,omitempty"`
+	Status GlanceStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// GlanceList contains a list of Glance
+type GlanceList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Glance `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Glance{}, &GlanceList{})
+}
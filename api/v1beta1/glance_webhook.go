@@ -17,11 +17,22 @@ limitations under the License.
 package v1beta1
 
 import (
-	"errors"
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 )
 
 // GlanceDefaults -
@@ -44,21 +55,35 @@ func SetupGlanceDefaults(defaults GlanceDefaults) {
 func (r *Glance) SetupWebhookWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewWebhookManagedBy(mgr).
 		For(r).
+		WithDefaulter(&GlanceCustomDefaulter{}).
+		WithValidator(&GlanceCustomValidator{Client: mgr.GetClient()}).
 		Complete()
 }
 
 //+kubebuilder:webhook:path=/mutate-glance-openstack-org-v1beta1-glance,mutating=true,failurePolicy=fail,sideEffects=None,groups=glance.openstack.org,resources=glances,verbs=create;update,versions=v1beta1,name=mglance.kb.io,admissionReviewVersions=v1
 
-var _ webhook.Defaulter = &Glance{}
+// GlanceCustomDefaulter defaults a Glance resource for use with the
+// webhook.CustomDefaulter interface
+type GlanceCustomDefaulter struct{}
+
+var _ webhook.CustomDefaulter = &GlanceCustomDefaulter{}
 
-// Default implements webhook.Defaulter so a webhook will be registered for the type
-func (r *Glance) Default() {
+// Default implements webhook.CustomDefaulter so a webhook will be registered for the type
+func (d *GlanceCustomDefaulter) Default(_ context.Context, obj runtime.Object) error {
+	r, ok := obj.(*Glance)
+	if !ok {
+		return apierrors.NewBadRequest(fmt.Sprintf("expected a Glance but got a %T", obj))
+	}
 	glancelog.Info("default", "name", r.Name)
 
 	r.Spec.Default()
+	return nil
 }
 
-// Check if the KeystoneEndpoint matches with a deployed glanceAPI
+// isValidKeystoneEP - checks the KeystoneEndpoint matches an entry of the
+// deprecated embedded GlanceAPIs map. Kept for instances still using that
+// inline form; GlanceCustomValidator.isRegisteredInCatalog is the
+// cross-CR equivalent for instances whose GlanceAPI sub-CRs already exist.
 func (spec *GlanceSpec) isValidKeystoneEP() bool {
 	for name := range spec.GlanceAPIs {
 		if spec.KeystoneEndpoint == name {
@@ -68,27 +93,154 @@ func (spec *GlanceSpec) isValidKeystoneEP() bool {
 	return false
 }
 
-// Default - set defaults for this Glance spec
+// isValidKeystoneEndpointType - returns true if t is one of the endpoint
+// interfaces (public/internal/admin) Keystone recognizes for a service
+// catalog entry
+func isValidKeystoneEndpointType(t KeystoneEndpointType) bool {
+	for _, valid := range AllKeystoneEndpointTypes {
+		if t == valid {
+			return true
+		}
+	}
+	return false
+}
+
+// validateGlanceAPIs - validates the common, non-mutating invariants of the
+// GlanceAPIs map that both ValidateCreate and ValidateUpdate rely on:
+// every service override endpoint type is a known Keystone interface and
+// exactly one instance is registered in the Keystone catalog.
+func (spec *GlanceSpec) validateGlanceAPIs(basePath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if len(spec.GlanceAPIs) == 0 {
+		return append(allErrs, field.Required(basePath.Child("glanceAPIs"), "at least one GlanceAPI instance must be defined"))
+	}
+
+	for name, glanceAPI := range spec.GlanceAPIs {
+		apiPath := basePath.Child("glanceAPIs").Key(name)
+		for endpointType := range glanceAPI.Override.Service {
+			if !isValidKeystoneEndpointType(endpointType) {
+				allErrs = append(allErrs, field.NotSupported(
+					apiPath.Child("override", "service").Key(string(endpointType)),
+					endpointType, toStrings(AllKeystoneEndpointTypes)))
+			}
+		}
+		if !glanceAPI.Backend.IsAccessModeAllowed(glanceAPI.Storage.AccessMode) {
+			allErrs = append(allErrs, field.NotSupported(
+				apiPath.Child("storage", "accessMode"), glanceAPI.Storage.AccessMode,
+				accessModeStrings(allowedBackendAccessModes[glanceAPI.Backend])))
+		}
+	}
+
+	if !spec.isValidKeystoneEP() {
+		allErrs = append(allErrs, field.Invalid(
+			basePath.Child("keystoneEndpoint"), spec.KeystoneEndpoint,
+			"must match the name of one of the GlanceAPI instances registered in the Keystone catalog"))
+	}
+
+	return allErrs
+}
+
+func toStrings(types []KeystoneEndpointType) []string {
+	out := make([]string, len(types))
+	for i, t := range types {
+		out[i] = string(t)
+	}
+	return out
+}
+
+// accessModeStrings - renders a slice of PVC access modes for use in a
+// field.ErrorList's list of supported values
+func accessModeStrings(modes []corev1.PersistentVolumeAccessMode) []string {
+	out := make([]string, len(modes))
+	for i, m := range modes {
+		out[i] = string(m)
+	}
+	return out
+}
+
+// immutableGlanceAPIField describes a GlanceAPITemplate field that cannot be
+// changed after creation. Fields marked migratable may still change once
+// spec.allowLayoutMigration is set: the controller then performs a
+// supervised transition instead of the webhook rejecting the update.
+type immutableGlanceAPIField struct {
+	name       string
+	migratable bool
+}
+
+// immutableGlanceAPIFields - the GlanceAPITemplate fields that define a
+// backend's identity and can't be changed once the instance is created
+var immutableGlanceAPIFields = []immutableGlanceAPIField{
+	{name: "Type", migratable: true},
+	{name: "Storage"},
+}
+
+// diffImmutableGlanceAPIFields - compares the immutable fields of old and new
+// via reflection, skipping fields marked migratable when allowMigration is
+// true, and returns a field.ErrorList for every one that changed. old and new
+// must be of the same struct type (GlanceAPITemplate or GlanceAPISpec) and
+// share the "Type"/"Storage" field names immutableGlanceAPIFields refers to.
+func diffImmutableGlanceAPIFields(old, new interface{}, allowMigration bool, basePath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	oldVal := reflect.ValueOf(old)
+	newVal := reflect.ValueOf(new)
+	for _, f := range immutableGlanceAPIFields {
+		if f.migratable && allowMigration {
+			continue
+		}
+		oldField := oldVal.FieldByName(f.name)
+		newField := newVal.FieldByName(f.name)
+		if !oldField.IsValid() || !newField.IsValid() {
+			continue
+		}
+		if !reflect.DeepEqual(oldField.Interface(), newField.Interface()) {
+			allErrs = append(allErrs, field.Forbidden(
+				basePath.Child(jsonFieldName(newVal.Type(), f.name)),
+				fmt.Sprintf("field is immutable, cannot change from %v to %v", oldField.Interface(), newField.Interface())))
+		}
+	}
+	return allErrs
+}
+
+// jsonFieldName - returns the JSON tag name for a struct field, falling back
+// to its Go field name when no tag is present
+func jsonFieldName(t reflect.Type, goName string) string {
+	sf, ok := t.FieldByName(goName)
+	if !ok {
+		return goName
+	}
+	name, _, _ := strings.Cut(sf.Tag.Get("json"), ",")
+	if name == "" || name == "-" {
+		return goName
+	}
+	return name
+}
+
+// Default - set defaults for this Glance spec. The GlanceAPIs map defaulted
+// here remains the user-facing, legacy inline form; the collapse onto a
+// first-class per-instance resource happens one layer up, in
+// GlanceReconciler.ensureGlanceAPIs, which owns one GlanceAPI CR per map
+// entry by owner-reference. An empty map is left untouched rather than
+// auto-populated: requiring at least one GlanceAPI instance is
+// validateGlanceAPIs's job, and defaulting it here would make that check
+// unreachable in a real cluster, since this mutating webhook always runs
+// before the validating one.
 func (spec *GlanceSpec) Default() {
 	if len(spec.ContainerImage) == 0 {
 		spec.ContainerImage = glanceDefaults.ContainerImageURL
 	}
-	// When no glanceAPI(s) are specified in the top-level CR
-	// we build one by default
-	// TODO: (fpantano) Set replicas=0 so users are forced to
-	// patch the CR and configure a backend.
-	if spec.GlanceAPIs == nil || len(spec.GlanceAPIs) == 0 {
-		// keystoneEndpoint will match with the only instance
-		// deployed by default
-		spec.KeystoneEndpoint = "default"
-		spec.GlanceAPIs = map[string]GlanceAPITemplate{
-			"default": {},
-		}
-	}
 	for key, glanceAPI := range spec.GlanceAPIs {
+		changed := false
 		// Check the sub-cr ContainerImage parameter
 		if glanceAPI.ContainerImage == "" {
 			glanceAPI.ContainerImage = glanceDefaults.ContainerImageURL
+			changed = true
+		}
+		if glanceAPI.Backend == "" {
+			glanceAPI.Backend = BackendFile
+			changed = true
+		}
+		if changed {
 			spec.GlanceAPIs[key] = glanceAPI
 		}
 	}
@@ -105,51 +257,164 @@ func (spec *GlanceSpec) Default() {
 
 //+kubebuilder:webhook:path=/validate-glance-openstack-org-v1beta1-glance,mutating=false,failurePolicy=fail,sideEffects=None,groups=glance.openstack.org,resources=glances,verbs=create;update,versions=v1beta1,name=vglance.kb.io,admissionReviewVersions=v1
 
-var _ webhook.Validator = &Glance{}
+// GlanceCustomValidator validates a Glance resource for use with the
+// webhook.CustomValidator interface
+type GlanceCustomValidator struct {
+	// Client - used to look up the GlanceAPI instances owned by the Glance
+	// under validation, so isRegisteredInCatalog can confirm one of them is
+	// the Keystone catalog entry instead of relying on the deprecated
+	// embedded GlanceAPIs map. Left nil in unit tests that don't exercise
+	// the cross-CR lookup.
+	Client client.Client
+}
 
-// ValidateCreate implements webhook.Validator so a webhook will be registered for the type
-func (r *Glance) ValidateCreate() error {
-	glancelog.Info("validate create", "name", r.Name)
-	// At creation time, if the CR has an invalid keystoneEndpoint value (that
-	// doesn't match with any defined backend), return an error.
-	if !r.Spec.isValidKeystoneEP() {
-		return errors.New("KeystoneEndpoint is assigned to an invalid glanceAPI instance")
+var _ webhook.CustomValidator = &GlanceCustomValidator{}
+
+// isRegisteredInCatalog - cross-CR replacement for the deprecated map-based
+// GlanceSpec.isValidKeystoneEP: lists the GlanceAPI instances owned by r and
+// confirms exactly one is marked spec.keystoneCatalog with a name matching
+// spec.keystoneEndpoint. Falls back to true when no owned GlanceAPI exists
+// yet (e.g. right after creation, before the controller has had a chance to
+// seed them) so the embedded-map validation in validateGlanceAPIs remains
+// authoritative until then.
+func (v *GlanceCustomValidator) isRegisteredInCatalog(ctx context.Context, r *Glance) (bool, error) {
+	if v.Client == nil {
+		return true, nil
 	}
 
-	//TODO:
-	// - Check one of the items of the list is the one that should appear in the
-	//   keystone catalog, otherwise raise an error because the field is not set!
-	return nil
+	var list GlanceAPIList
+	if err := v.Client.List(ctx, &list, client.InNamespace(r.Namespace)); err != nil {
+		return false, err
+	}
+
+	var owned bool
+	for i := range list.Items {
+		api := &list.Items[i]
+		if !metav1.IsControlledBy(api, r) {
+			continue
+		}
+		owned = true
+		if api.Spec.KeystoneCatalog && api.Name == r.Spec.KeystoneEndpoint {
+			return true, nil
+		}
+	}
+	return !owned, nil
 }
 
-// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
-func (r *Glance) ValidateUpdate(old runtime.Object) error {
+// ValidateCreate implements webhook.CustomValidator so a webhook will be registered for the type
+func (v *GlanceCustomValidator) ValidateCreate(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+	r, ok := obj.(*Glance)
+	if !ok {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("expected a Glance but got a %T", obj))
+	}
+	glancelog.Info("validate create", "name", r.Name)
+
+	warnings := r.Spec.defaultingWarnings()
+
+	if allErrs := r.Spec.validateGlanceAPIs(field.NewPath("spec")); len(allErrs) > 0 {
+		return warnings, apierrors.NewInvalid(
+			schema.GroupKind{Group: "glance.openstack.org", Kind: "Glance"},
+			r.Name, allErrs)
+	}
+	return warnings, nil
+}
+
+// ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type
+func (v *GlanceCustomValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	r, ok := newObj.(*Glance)
+	if !ok {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("expected a Glance but got a %T", newObj))
+	}
+	o, ok := oldObj.(*Glance)
+	if !ok {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("expected a Glance but got a %T", oldObj))
+	}
 	glancelog.Info("validate update", "name", r.Name)
 
-	// Type can either be "split" or "single": we do not support changing layout
-	// because there's no logic in the operator to scale down the existing statefulset
-	// and scale up the new one, hence updating the Spec.GlanceAPI.Type is not supported
-	o := old.(*Glance)
+	warnings := r.Spec.defaultingWarnings()
+	allErrs := r.Spec.validateGlanceAPIs(field.NewPath("spec"))
+
+	switch registered, err := v.isRegisteredInCatalog(ctx, r); {
+	case err != nil:
+		return warnings, err
+	case !registered:
+		allErrs = append(allErrs, field.Invalid(
+			field.NewPath("spec", "keystoneEndpoint"), r.Spec.KeystoneEndpoint,
+			"must match the name of a GlanceAPI instance owned by this Glance with keystoneCatalog set"))
+	}
+
+	// The GlanceAPITemplate fields listed in immutableGlanceAPIFields
+	// (deployment layout, storage backend class/access mode) define the
+	// identity of the backend and can't be changed in place. When spec.allowLayoutMigration is set, a Type change
+	// is let through so the controller can perform a supervised transition
+	// instead (see the GlanceAPI layout migration subsystem).
 	for key, glanceAPI := range r.Spec.GlanceAPIs {
 		// When a new entry (new glanceAPI instance) is added in the main CR, it's
 		// possible that the old CR used to compare the new map had no entry with
 		// the same name. This represent a valid use case and we shouldn't prevent
 		// to grow the deployment
-		if _, found := o.Spec.GlanceAPIs[key]; !found {
+		oldGlanceAPI, found := o.Spec.GlanceAPIs[key]
+		if !found {
 			continue
 		}
-		// The current glanceAPI exists and the layout is different
-		if glanceAPI.Type != o.Spec.GlanceAPIs[key].Type {
-			return errors.New("GlanceAPI deployment layout can't be updated")
-		}
+		allErrs = append(allErrs, diffImmutableGlanceAPIFields(
+			oldGlanceAPI, glanceAPI, r.Spec.AllowLayoutMigration,
+			field.NewPath("spec", "glanceAPIs").Key(key))...)
 	}
-	return nil
+
+	// KeystoneEndpoint is immutable once it has been bound to a catalog
+	// entry: re-pointing it would orphan the previously registered endpoint.
+	if o.Spec.KeystoneEndpoint != "" && r.Spec.KeystoneEndpoint != o.Spec.KeystoneEndpoint {
+		allErrs = append(allErrs, field.Forbidden(
+			field.NewPath("spec", "keystoneEndpoint"),
+			fmt.Sprintf("field is immutable once bound, cannot change from %q to %q", o.Spec.KeystoneEndpoint, r.Spec.KeystoneEndpoint)))
+	}
+
+	if len(allErrs) > 0 {
+		return warnings, apierrors.NewInvalid(
+			schema.GroupKind{Group: "glance.openstack.org", Kind: "Glance"},
+			r.Name, allErrs)
+	}
+	return warnings, nil
 }
 
-// ValidateDelete implements webhook.Validator so a webhook will be registered for the type
-func (r *Glance) ValidateDelete() error {
+// ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type
+func (v *GlanceCustomValidator) ValidateDelete(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+	r, ok := obj.(*Glance)
+	if !ok {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("expected a Glance but got a %T", obj))
+	}
 	glancelog.Info("validate delete", "name", r.Name)
 
 	// TODO(user): fill in your validation logic upon object deletion.
-	return nil
+	return nil, nil
+}
+
+// defaultingWarnings - surfaces non-fatal notices for spec values that match
+// what Default fills in implicitly, so cluster admins see them in their
+// `kubectl apply` output instead of the mutation happening silently. Only the
+// defaulted spec is inspected, so a value the user set explicitly to that
+// same default is reported the same way.
+func (spec *GlanceSpec) defaultingWarnings() admission.Warnings {
+	var warnings admission.Warnings
+
+	if spec.ContainerImage == glanceDefaults.ContainerImageURL {
+		warnings = append(warnings, fmt.Sprintf("spec.containerImage is not set: falling back to the operator-wide default %q", glanceDefaults.ContainerImageURL))
+	}
+
+	for name, glanceAPI := range spec.GlanceAPIs {
+		if glanceAPI.ContainerImage == glanceDefaults.ContainerImageURL {
+			warnings = append(warnings, fmt.Sprintf("spec.glanceAPIs[%s].containerImage is not set: falling back to the operator-wide default %q", name, glanceDefaults.ContainerImageURL))
+		}
+	}
+
+	if len(spec.GlanceAPIs) == 1 {
+		for name := range spec.GlanceAPIs {
+			if spec.KeystoneEndpoint == name {
+				warnings = append(warnings, fmt.Sprintf("spec.keystoneEndpoint is not set: inferring the only configured glanceAPI instance %q as the one registered in the Keystone catalog", name))
+			}
+		}
+	}
+
+	return warnings
 }
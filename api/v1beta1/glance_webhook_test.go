@@ -0,0 +1,201 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+func TestGlanceSpecDefaultingWarnings(t *testing.T) {
+	SetupGlanceDefaults(GlanceDefaults{ContainerImageURL: "quay.io/example/glance:default"})
+
+	tests := []struct {
+		name string
+		spec GlanceSpec
+		want int
+	}{
+		{
+			name: "everything explicit with multiple instances, no warnings",
+			spec: GlanceSpec{
+				ContainerImage:   "quay.io/example/glance:custom",
+				KeystoneEndpoint: "external",
+				GlanceAPIs: map[string]GlanceAPITemplate{
+					"external": {ContainerImage: "quay.io/example/glance:custom"},
+					"internal": {ContainerImage: "quay.io/example/glance:custom"},
+				},
+			},
+			want: 0,
+		},
+		{
+			name: "containerImage falls back to operator default",
+			spec: GlanceSpec{
+				ContainerImage:   "quay.io/example/glance:default",
+				KeystoneEndpoint: "external",
+				GlanceAPIs: map[string]GlanceAPITemplate{
+					"external": {ContainerImage: "quay.io/example/glance:custom"},
+					"internal": {ContainerImage: "quay.io/example/glance:custom"},
+				},
+			},
+			want: 1,
+		},
+		{
+			name: "per-instance containerImage falls back to operator default",
+			spec: GlanceSpec{
+				ContainerImage:   "quay.io/example/glance:custom",
+				KeystoneEndpoint: "external",
+				GlanceAPIs: map[string]GlanceAPITemplate{
+					"external": {ContainerImage: "quay.io/example/glance:default"},
+					"internal": {ContainerImage: "quay.io/example/glance:custom"},
+				},
+			},
+			want: 1,
+		},
+		{
+			name: "single instance infers keystoneEndpoint",
+			spec: GlanceSpec{
+				ContainerImage:   "quay.io/example/glance:custom",
+				KeystoneEndpoint: "default",
+				GlanceAPIs: map[string]GlanceAPITemplate{
+					"default": {ContainerImage: "quay.io/example/glance:custom"},
+				},
+			},
+			want: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.spec.defaultingWarnings(); len(got) != tt.want {
+				t.Errorf("defaultingWarnings() = %v, want %d warning(s)", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiffImmutableGlanceAPIFields(t *testing.T) {
+	base := GlanceAPITemplate{
+		Type:    "single",
+		Storage: Storage{AccessMode: "ReadWriteOnce"},
+	}
+
+	tests := []struct {
+		name           string
+		old, new       GlanceAPITemplate
+		allowMigration bool
+		wantErrs       int
+	}{
+		{
+			name:     "no change",
+			old:      base,
+			new:      base,
+			wantErrs: 0,
+		},
+		{
+			name: "type change rejected without allowMigration",
+			old:  base,
+			new: func() GlanceAPITemplate {
+				t := base
+				t.Type = "split"
+				return t
+			}(),
+			allowMigration: false,
+			wantErrs:       1,
+		},
+		{
+			name: "type change allowed with allowMigration",
+			old:  base,
+			new: func() GlanceAPITemplate {
+				t := base
+				t.Type = "split"
+				return t
+			}(),
+			allowMigration: true,
+			wantErrs:       0,
+		},
+		{
+			name: "storage change always rejected, even with allowMigration",
+			old:  base,
+			new: func() GlanceAPITemplate {
+				t := base
+				t.Storage.AccessMode = "ReadWriteMany"
+				return t
+			}(),
+			allowMigration: true,
+			wantErrs:       1,
+		},
+		{
+			name: "type and storage both changed yields two errors",
+			old:  base,
+			new: func() GlanceAPITemplate {
+				t := base
+				t.Type = "split"
+				t.Storage.AccessMode = "ReadWriteMany"
+				return t
+			}(),
+			allowMigration: false,
+			wantErrs:       2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := diffImmutableGlanceAPIFields(tt.old, tt.new, tt.allowMigration, field.NewPath("spec"))
+			if len(errs) != tt.wantErrs {
+				t.Errorf("diffImmutableGlanceAPIFields() = %v, want %d error(s)", errs, tt.wantErrs)
+			}
+		})
+	}
+}
+
+func TestDiffImmutableGlanceAPIFieldsAcrossStructTypes(t *testing.T) {
+	// diffImmutableGlanceAPIFields is also called with old/new of type
+	// GlanceAPISpec (GlanceAPICustomValidator.ValidateUpdate), so it must
+	// behave the same way regardless of which struct carries the fields.
+	old := GlanceAPISpec{Type: "single", Storage: Storage{AccessMode: "ReadWriteOnce"}}
+	new := GlanceAPISpec{Type: "split", Storage: Storage{AccessMode: "ReadWriteOnce"}}
+
+	if errs := diffImmutableGlanceAPIFields(old, new, false, field.NewPath("spec")); len(errs) != 1 {
+		t.Errorf("diffImmutableGlanceAPIFields() = %v, want 1 error", errs)
+	}
+	if errs := diffImmutableGlanceAPIFields(old, new, true, field.NewPath("spec")); len(errs) != 0 {
+		t.Errorf("diffImmutableGlanceAPIFields() = %v, want 0 errors", errs)
+	}
+}
+
+func TestJSONFieldName(t *testing.T) {
+	tests := []struct {
+		name   string
+		goName string
+		want   string
+	}{
+		{name: "tagged field", goName: "Type", want: "type"},
+		{name: "tagged field with options", goName: "Storage", want: "storage"},
+		{name: "unknown go field falls back to go name", goName: "DoesNotExist", want: "DoesNotExist"},
+	}
+
+	typ := reflect.TypeOf(GlanceAPITemplate{})
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := jsonFieldName(typ, tt.goName); got != tt.want {
+				t.Errorf("jsonFieldName() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
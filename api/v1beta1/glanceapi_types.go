@@ -0,0 +1,115 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// GlanceAPISpec defines the desired state of a single GlanceAPI instance.
+// It carries the same fields GlanceAPITemplate used to hold inline in
+// GlanceSpec.GlanceAPIs, now promoted to their own CR so each instance gets
+// independent status, rolling updates and RBAC.
+type GlanceAPISpec struct {
+	// ContainerImage - the container image used by this GlanceAPI instance.
+	// When empty, the operator-wide default is used.
+	// +kubebuilder:validation:Optional
+	ContainerImage string `json:"containerImage,omitempty"`
+
+	// Backend - the storage backend this GlanceAPI instance is configured
+	// for. Determines which PVC access modes Storage.AccessMode may use; see
+	// BackendType.IsAccessModeAllowed.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=file
+	Backend BackendType `json:"backend,omitempty"`
+
+	// Type - the deployment layout for this GlanceAPI: "single" runs the API
+	// and backend in one StatefulSet, "split" separates external and
+	// internal API instances. Immutable unless the owning Glance's
+	// spec.allowLayoutMigration is set, in which case the controller
+	// performs a supervised transition.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=single;split
+	// +kubebuilder:default=single
+	Type string `json:"type,omitempty"`
+
+	// Replicas - the number of GlanceAPI replicas to run
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=1
+	Replicas *int32 `json:"replicas,omitempty"`
+
+	// Storage - the storage backend class and access mode backing this
+	// GlanceAPI instance. Immutable once set.
+	// +kubebuilder:validation:Optional
+	Storage Storage `json:"storage,omitempty"`
+
+	// Override - per Keystone endpoint type overrides for the Service/Route
+	// created for this GlanceAPI
+	// +kubebuilder:validation:Optional
+	Override Override `json:"override,omitempty"`
+
+	// KeystoneCatalog - marks this GlanceAPI instance as the one registered
+	// in the Keystone service catalog for the owning Glance. Exactly one
+	// GlanceAPI per Glance should set this.
+	// +kubebuilder:validation:Optional
+	KeystoneCatalog bool `json:"keystoneCatalog,omitempty"`
+
+	// AllowLayoutMigration - mirrors the owning Glance's
+	// spec.allowLayoutMigration for the duration of a Type change. The
+	// GlanceReconciler sets this before updating Type on the owned
+	// GlanceAPI so this instance's own validating webhook lets the
+	// supervised transition through instead of rejecting it.
+	// +kubebuilder:validation:Optional
+	AllowLayoutMigration bool `json:"allowLayoutMigration,omitempty"`
+}
+
+// GlanceAPIStatus defines the observed state of a GlanceAPI instance
+type GlanceAPIStatus struct {
+	// Conditions - the status of this GlanceAPI instance
+	// +kubebuilder:validation:Optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// ReadyReplicas - the number of GlanceAPI replicas currently ready
+	// +kubebuilder:validation:Optional
+	ReadyReplicas int32 `json:"readyReplicas,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+
+// GlanceAPI is the Schema for the glanceapis API. Instances are owned by a
+// parent Glance via owner-reference rather than embedded as a map entry.
+type GlanceAPI struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   GlanceAPISpec   `json:"spec,omitempty"`
+	Status GlanceAPIStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// GlanceAPIList contains a list of GlanceAPI
+type GlanceAPIList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []GlanceAPI `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&GlanceAPI{}, &GlanceAPIList{})
+}
@@ -0,0 +1,153 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"context"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// glanceapilog is for logging in this package.
+var glanceapilog = logf.Log.WithName("glanceapi-resource")
+
+// SetupWebhookWithManager sets up the webhook with the Manager
+func (r *GlanceAPI) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(r).
+		WithDefaulter(&GlanceAPICustomDefaulter{}).
+		WithValidator(&GlanceAPICustomValidator{}).
+		Complete()
+}
+
+//+kubebuilder:webhook:path=/mutate-glance-openstack-org-v1beta1-glanceapi,mutating=true,failurePolicy=fail,sideEffects=None,groups=glance.openstack.org,resources=glanceapis,verbs=create;update,versions=v1beta1,name=mglanceapi.kb.io,admissionReviewVersions=v1
+
+// GlanceAPICustomDefaulter defaults a GlanceAPI resource for use with the
+// webhook.CustomDefaulter interface
+type GlanceAPICustomDefaulter struct{}
+
+var _ webhook.CustomDefaulter = &GlanceAPICustomDefaulter{}
+
+// Default implements webhook.CustomDefaulter so a webhook will be registered for the type
+func (d *GlanceAPICustomDefaulter) Default(_ context.Context, obj runtime.Object) error {
+	r, ok := obj.(*GlanceAPI)
+	if !ok {
+		return apierrors.NewBadRequest(fmt.Sprintf("expected a GlanceAPI but got a %T", obj))
+	}
+	glanceapilog.Info("default", "name", r.Name)
+
+	if r.Spec.ContainerImage == "" {
+		r.Spec.ContainerImage = glanceDefaults.ContainerImageURL
+	}
+	if r.Spec.Backend == "" {
+		r.Spec.Backend = BackendFile
+	}
+	if r.Spec.Type == "" {
+		r.Spec.Type = DefaultGlanceAPITemplate(r.Spec.Backend).Type
+	}
+	return nil
+}
+
+//+kubebuilder:webhook:path=/validate-glance-openstack-org-v1beta1-glanceapi,mutating=false,failurePolicy=fail,sideEffects=None,groups=glance.openstack.org,resources=glanceapis,verbs=create;update,versions=v1beta1,name=vglanceapi.kb.io,admissionReviewVersions=v1
+
+// GlanceAPICustomValidator validates a GlanceAPI resource for use with the
+// webhook.CustomValidator interface
+type GlanceAPICustomValidator struct{}
+
+var _ webhook.CustomValidator = &GlanceAPICustomValidator{}
+
+// validateGlanceAPISpec - validates the invariants both ValidateCreate and
+// ValidateUpdate rely on: every service override endpoint type is a known
+// Keystone interface, and Storage.AccessMode is one the backend supports
+func (spec *GlanceAPISpec) validateGlanceAPISpec(basePath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	for endpointType := range spec.Override.Service {
+		if !isValidKeystoneEndpointType(endpointType) {
+			allErrs = append(allErrs, field.NotSupported(
+				basePath.Child("override", "service").Key(string(endpointType)),
+				endpointType, toStrings(AllKeystoneEndpointTypes)))
+		}
+	}
+	if !spec.Backend.IsAccessModeAllowed(spec.Storage.AccessMode) {
+		allErrs = append(allErrs, field.NotSupported(
+			basePath.Child("storage", "accessMode"), spec.Storage.AccessMode,
+			accessModeStrings(allowedBackendAccessModes[spec.Backend])))
+	}
+	return allErrs
+}
+
+// ValidateCreate implements webhook.CustomValidator so a webhook will be registered for the type
+func (v *GlanceAPICustomValidator) ValidateCreate(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+	r, ok := obj.(*GlanceAPI)
+	if !ok {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("expected a GlanceAPI but got a %T", obj))
+	}
+	glanceapilog.Info("validate create", "name", r.Name)
+
+	if allErrs := r.Spec.validateGlanceAPISpec(field.NewPath("spec")); len(allErrs) > 0 {
+		return nil, apierrors.NewInvalid(
+			schema.GroupKind{Group: "glance.openstack.org", Kind: "GlanceAPI"},
+			r.Name, allErrs)
+	}
+	return nil, nil
+}
+
+// ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type
+func (v *GlanceAPICustomValidator) ValidateUpdate(_ context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	r, ok := newObj.(*GlanceAPI)
+	if !ok {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("expected a GlanceAPI but got a %T", newObj))
+	}
+	o, ok := oldObj.(*GlanceAPI)
+	if !ok {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("expected a GlanceAPI but got a %T", oldObj))
+	}
+	glanceapilog.Info("validate update", "name", r.Name)
+
+	allErrs := r.Spec.validateGlanceAPISpec(field.NewPath("spec"))
+
+	// r.Spec.AllowLayoutMigration mirrors the owning Glance's
+	// spec.allowLayoutMigration: GlanceReconciler.ensureGlanceAPIs sets it
+	// before pushing a Type change down onto this GlanceAPI, so a migration
+	// approved at the Glance level isn't rejected again here.
+	allErrs = append(allErrs, diffImmutableGlanceAPIFields(o.Spec, r.Spec, r.Spec.AllowLayoutMigration, field.NewPath("spec"))...)
+
+	if len(allErrs) > 0 {
+		return nil, apierrors.NewInvalid(
+			schema.GroupKind{Group: "glance.openstack.org", Kind: "GlanceAPI"},
+			r.Name, allErrs)
+	}
+	return nil, nil
+}
+
+// ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type
+func (v *GlanceAPICustomValidator) ValidateDelete(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+	r, ok := obj.(*GlanceAPI)
+	if !ok {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("expected a GlanceAPI but got a %T", obj))
+	}
+	glanceapilog.Info("validate delete", "name", r.Name)
+	return nil, nil
+}
@@ -0,0 +1,178 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Command resource-generator reads a YAML descriptor of the GlanceAPI
+// storage backend variants this operator supports and emits the boilerplate
+// parts of api/v1beta1 (backend constants, default GlanceAPITemplate
+// constructors, and per-backend access-mode validation) so adding a new
+// backend is a matter of dropping a descriptor rather than hand-editing
+// GlanceSpec.Default and glanceapi_webhook.go.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"strings"
+	"text/template"
+
+	"gopkg.in/yaml.v3"
+)
+
+// backendDescriptor - one entry of the backends.yaml schema
+type backendDescriptor struct {
+	Name               string   `yaml:"name"`
+	DefaultType        string   `yaml:"defaultType"`
+	AccessMode         string   `yaml:"accessMode"`
+	AllowedAccessModes []string `yaml:"allowedAccessModes"`
+}
+
+type schema struct {
+	Backends []backendDescriptor `yaml:"backends"`
+}
+
+const tmplSource = `// Code generated by resource-generator from {{.Source}}. DO NOT EDIT.
+
+package v1beta1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+)
+
+// BackendType identifies a supported GlanceAPI storage backend
+type BackendType string
+
+const (
+{{- range .Backends}}
+	// Backend{{.GoName}} - the {{.Name}} storage backend
+	Backend{{.GoName}} BackendType = "{{.Name}}"
+{{- end}}
+)
+
+// AllBackendTypes - every storage backend this operator ships a default
+// GlanceAPITemplate for
+var AllBackendTypes = []BackendType{
+{{- range .Backends}}
+	Backend{{.GoName}},
+{{- end}}
+}
+
+// DefaultGlanceAPITemplate returns the default GlanceAPITemplate for the
+// given backend, as described in {{.Source}}
+func DefaultGlanceAPITemplate(backend BackendType) GlanceAPITemplate {
+	switch backend {
+{{- range .Backends}}
+	case Backend{{.GoName}}:
+		return GlanceAPITemplate{
+			Backend: Backend{{.GoName}},
+			Type:    "{{.DefaultType}}",
+			Storage: Storage{AccessMode: "{{.AccessMode}}"},
+		}
+{{- end}}
+	default:
+		return GlanceAPITemplate{}
+	}
+}
+
+// allowedBackendAccessModes - the PVC access modes each backend supports, as
+// described in {{.Source}}
+var allowedBackendAccessModes = map[BackendType][]corev1.PersistentVolumeAccessMode{
+{{- range .Backends}}
+	Backend{{.GoName}}: {
+{{- range .AllowedAccessModes}}
+		"{{.}}",
+{{- end}}
+	},
+{{- end}}
+}
+
+// IsAccessModeAllowed returns true if mode is one of the PVC access modes
+// backend supports. An unrecognized backend allows nothing.
+func (backend BackendType) IsAccessModeAllowed(mode corev1.PersistentVolumeAccessMode) bool {
+	for _, allowed := range allowedBackendAccessModes[backend] {
+		if allowed == mode {
+			return true
+		}
+	}
+	return false
+}
+`
+
+type templateBackend struct {
+	backendDescriptor
+	GoName string
+}
+
+type templateData struct {
+	Source   string
+	Backends []templateBackend
+}
+
+func main() {
+	schemaPath := flag.String("schema", "config/resource-generator/backends.yaml", "path to the backend descriptor schema")
+	outPath := flag.String("out", "api/v1beta1/zz_generated_backends.go", "path to write the generated Go source to")
+	flag.Parse()
+
+	if err := run(*schemaPath, *outPath); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func run(schemaPath, outPath string) error {
+	raw, err := os.ReadFile(schemaPath)
+	if err != nil {
+		return fmt.Errorf("reading %s: %w", schemaPath, err)
+	}
+
+	var s schema
+	if err := yaml.Unmarshal(raw, &s); err != nil {
+		return fmt.Errorf("parsing %s: %w", schemaPath, err)
+	}
+
+	data := templateData{Source: schemaPath}
+	for _, b := range s.Backends {
+		data.Backends = append(data.Backends, templateBackend{backendDescriptor: b, GoName: exportedName(b.Name)})
+	}
+
+	tmpl, err := template.New("backends").Parse(tmplSource)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return err
+	}
+
+	formatted, err := format.Source(buf.Bytes())
+	if err != nil {
+		return fmt.Errorf("formatting generated source: %w", err)
+	}
+
+	return os.WriteFile(outPath, formatted, 0644)
+}
+
+// exportedName - capitalizes a backend name for use as a Go identifier, e.g.
+// "rbd" -> "Rbd"
+func exportedName(name string) string {
+	if name == "" {
+		return name
+	}
+	return strings.ToUpper(name[:1]) + name[1:]
+}
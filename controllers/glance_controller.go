@@ -0,0 +1,245 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	k8s_errors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	glancev1 "github.com/openstack-k8s-operators/glance-operator/api/v1beta1"
+)
+
+// layoutMigrationRequeueDelay - how long to wait between steps of a
+// supervised GlanceAPI layout transition while the new StatefulSet comes up
+// or the old one drains
+const layoutMigrationRequeueDelay = 10 * time.Second
+
+// GlanceReconciler reconciles a Glance object
+type GlanceReconciler struct {
+	client.Client
+	Kclient kubernetes.Interface
+	Scheme  *runtime.Scheme
+}
+
+//+kubebuilder:rbac:groups=glance.openstack.org,resources=glances,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=glance.openstack.org,resources=glances/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=glance.openstack.org,resources=glanceapis,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=apps,resources=statefulsets,verbs=get;list;watch;create;update;patch;delete
+
+// Reconcile drives the Glance instance towards its desired state. Besides
+// the usual sub-resource reconciliation, it owns one GlanceAPI CR per
+// spec.GlanceAPIs entry and steps any layout migration recorded in
+// status.layoutMigrationsInProgress until it completes.
+func (r *GlanceReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	instance := &glancev1.Glance{}
+	if err := r.Client.Get(ctx, req.NamespacedName, instance); err != nil {
+		if k8s_errors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	if err := r.ensureGlanceAPIs(ctx, instance); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	return r.stepLayoutMigrations(ctx, instance)
+}
+
+// ensureGlanceAPIs - owns one GlanceAPI CR per spec.GlanceAPIs entry,
+// keyed by the same name, so each instance gets independent status, rolling
+// updates and RBAC instead of being embedded as a map value, and prunes
+// owned GlanceAPIs whose entry was since removed. The entry matching
+// spec.KeystoneEndpoint is the one marked keystoneCatalog, which
+// GlanceCustomValidator.isRegisteredInCatalog reads back via a cross-CR
+// lookup. A Type change detected here is what seeds
+// status.layoutMigrationsInProgress; stepLayoutMigrations drives it from
+// there.
+func (r *GlanceReconciler) ensureGlanceAPIs(ctx context.Context, instance *glancev1.Glance) error {
+	statusChanged := false
+	for name, tmpl := range instance.Spec.GlanceAPIs {
+		glanceAPI := &glancev1.GlanceAPI{}
+		glanceAPI.Name = name
+		glanceAPI.Namespace = instance.Namespace
+
+		if _, err := controllerutil.CreateOrUpdate(ctx, r.Client, glanceAPI, func() error {
+			// CreateOrUpdate already fetched the existing GlanceAPI (zero
+			// value Type for one that doesn't exist yet) before invoking
+			// this mutate function, so this is the layout we'd be
+			// transitioning away from.
+			oldType := glanceAPI.Spec.Type
+			migrating := oldType != "" && oldType != tmpl.Type && instance.Spec.AllowLayoutMigration
+
+			glanceAPI.Spec.ContainerImage = tmpl.ContainerImage
+			glanceAPI.Spec.Backend = tmpl.Backend
+			glanceAPI.Spec.Type = tmpl.Type
+			glanceAPI.Spec.Replicas = tmpl.Replicas
+			glanceAPI.Spec.Storage = tmpl.Storage
+			glanceAPI.Spec.Override = tmpl.Override
+			glanceAPI.Spec.KeystoneCatalog = name == instance.Spec.KeystoneEndpoint
+			// Mirror the opt-in onto the owned GlanceAPI for the duration
+			// of the migration so its own validating webhook, which has no
+			// access to the parent spec, lets the Type change through too.
+			glanceAPI.Spec.AllowLayoutMigration = migrating || containsString(instance.Status.LayoutMigrationsInProgress, name)
+
+			if migrating && !containsString(instance.Status.LayoutMigrationsInProgress, name) {
+				instance.Status.LayoutMigrationsInProgress = append(instance.Status.LayoutMigrationsInProgress, name)
+				statusChanged = true
+			}
+
+			return controllerutil.SetControllerReference(instance, glanceAPI, r.Scheme)
+		}); err != nil {
+			return err
+		}
+	}
+
+	if statusChanged {
+		if err := r.Client.Status().Update(ctx, instance); err != nil {
+			return err
+		}
+	}
+
+	return r.pruneGlanceAPIs(ctx, instance)
+}
+
+// pruneGlanceAPIs - deletes owned GlanceAPI instances whose entry was
+// removed from spec.GlanceAPIs. Since each instance is now a standalone CR
+// rather than an embedded map value, removing a map key no longer deletes
+// it on its own; without this the instance (and its StatefulSet, and its
+// Keystone catalog registration if keystoneCatalog was set) would run
+// forever.
+func (r *GlanceReconciler) pruneGlanceAPIs(ctx context.Context, instance *glancev1.Glance) error {
+	var list glancev1.GlanceAPIList
+	if err := r.Client.List(ctx, &list, client.InNamespace(instance.Namespace)); err != nil {
+		return err
+	}
+
+	for i := range list.Items {
+		glanceAPI := &list.Items[i]
+		if !metav1.IsControlledBy(glanceAPI, instance) {
+			continue
+		}
+		if _, found := instance.Spec.GlanceAPIs[glanceAPI.Name]; found {
+			continue
+		}
+		if err := r.Client.Delete(ctx, glanceAPI); err != nil && !k8s_errors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// stepLayoutMigrations - advances every GlanceAPI layout migration recorded
+// in status.layoutMigrationsInProgress by one step, clearing an entry (and
+// the AllowLayoutMigration flag it set on the owned GlanceAPI) once
+// ensureLayoutMigration reports it complete, and requeues while any remain.
+func (r *GlanceReconciler) stepLayoutMigrations(ctx context.Context, instance *glancev1.Glance) (ctrl.Result, error) {
+	if len(instance.Status.LayoutMigrationsInProgress) == 0 {
+		return ctrl.Result{}, nil
+	}
+
+	var stillMigrating []string
+	statusChanged := false
+	for _, name := range instance.Status.LayoutMigrationsInProgress {
+		glanceAPI, found := instance.Spec.GlanceAPIs[name]
+		if !found {
+			// The entry was removed from spec: nothing left to migrate.
+			statusChanged = true
+			continue
+		}
+
+		result, err := r.ensureLayoutMigration(ctx, instance, name, otherLayout(glanceAPI.Type), glanceAPI.Type)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if !result.IsZero() {
+			stillMigrating = append(stillMigrating, name)
+			continue
+		}
+
+		// ensureLayoutMigration reported completion: drop the migration
+		// flag on the owned GlanceAPI so its webhook goes back to rejecting
+		// Type changes, and clear the status entry.
+		if err := r.clearLayoutMigration(ctx, instance, name); err != nil {
+			return ctrl.Result{}, err
+		}
+		statusChanged = true
+	}
+
+	if statusChanged {
+		instance.Status.LayoutMigrationsInProgress = stillMigrating
+		if err := r.Client.Status().Update(ctx, instance); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	if len(stillMigrating) > 0 {
+		return ctrl.Result{RequeueAfter: layoutMigrationRequeueDelay}, nil
+	}
+	return ctrl.Result{}, nil
+}
+
+// clearLayoutMigration - resets AllowLayoutMigration on the named GlanceAPI
+// now that its migration has completed
+func (r *GlanceReconciler) clearLayoutMigration(ctx context.Context, instance *glancev1.Glance, name string) error {
+	glanceAPI := &glancev1.GlanceAPI{}
+	if err := r.Client.Get(ctx, types.NamespacedName{Name: name, Namespace: instance.Namespace}, glanceAPI); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	if !glanceAPI.Spec.AllowLayoutMigration {
+		return nil
+	}
+	glanceAPI.Spec.AllowLayoutMigration = false
+	return r.Client.Update(ctx, glanceAPI)
+}
+
+// containsString - true if s is present in list
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// otherLayout - the deployment layout a GlanceAPI is transitioning away from
+func otherLayout(layout string) string {
+	if layout == "split" {
+		return "single"
+	}
+	return "split"
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *GlanceReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&glancev1.Glance{}).
+		Owns(&glancev1.GlanceAPI{}).
+		Owns(&appsv1.StatefulSet{}).
+		Complete(r)
+}
@@ -0,0 +1,154 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	glancev1 "github.com/openstack-k8s-operators/glance-operator/api/v1beta1"
+)
+
+func newTestScheme(t *testing.T) *runtime.Scheme {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	if err := glancev1.AddToScheme(scheme); err != nil {
+		t.Fatalf("AddToScheme(glancev1) = %v", err)
+	}
+	if err := appsv1.AddToScheme(scheme); err != nil {
+		t.Fatalf("AddToScheme(appsv1) = %v", err)
+	}
+	return scheme
+}
+
+func newTestGlance(name, namespace string, apis map[string]glancev1.GlanceAPITemplate) *glancev1.Glance {
+	return &glancev1.Glance{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec:       glancev1.GlanceSpec{GlanceAPIs: apis},
+	}
+}
+
+func TestEnsureGlanceAPIsPrunesRemovedEntries(t *testing.T) {
+	scheme := newTestScheme(t)
+	instance := newTestGlance("glance", "openstack", map[string]glancev1.GlanceAPITemplate{
+		"external": {Type: "single"},
+	})
+
+	builder := fake.NewClientBuilder().WithScheme(scheme).WithObjects(instance).WithStatusSubresource(instance)
+	r := &GlanceReconciler{Client: builder.Build(), Scheme: scheme}
+	ctx := context.Background()
+
+	if err := r.ensureGlanceAPIs(ctx, instance); err != nil {
+		t.Fatalf("ensureGlanceAPIs() = %v", err)
+	}
+
+	// Drop the entry and reconcile again: the owned GlanceAPI should be
+	// pruned rather than left running with nothing left referencing it.
+	instance.Spec.GlanceAPIs = map[string]glancev1.GlanceAPITemplate{}
+	if err := r.ensureGlanceAPIs(ctx, instance); err != nil {
+		t.Fatalf("ensureGlanceAPIs() second call = %v", err)
+	}
+
+	glanceAPI := &glancev1.GlanceAPI{}
+	err := r.Client.Get(ctx, types.NamespacedName{Name: "external", Namespace: "openstack"}, glanceAPI)
+	if err == nil {
+		t.Fatalf("GlanceAPI %q still exists after its spec.GlanceAPIs entry was removed", "external")
+	}
+}
+
+func TestEnsureGlanceAPIsSeedsLayoutMigration(t *testing.T) {
+	scheme := newTestScheme(t)
+	instance := newTestGlance("glance", "openstack", map[string]glancev1.GlanceAPITemplate{
+		"external": {Type: "single"},
+	})
+	instance.Spec.AllowLayoutMigration = true
+
+	builder := fake.NewClientBuilder().WithScheme(scheme).WithObjects(instance).WithStatusSubresource(instance)
+	r := &GlanceReconciler{Client: builder.Build(), Scheme: scheme}
+	ctx := context.Background()
+
+	if err := r.ensureGlanceAPIs(ctx, instance); err != nil {
+		t.Fatalf("ensureGlanceAPIs() = %v", err)
+	}
+
+	// First reconcile only creates the GlanceAPI at its initial Type: no
+	// migration to seed yet since there's no prior Type to diff against.
+	if len(instance.Status.LayoutMigrationsInProgress) != 0 {
+		t.Fatalf("LayoutMigrationsInProgress = %v, want none after initial create", instance.Status.LayoutMigrationsInProgress)
+	}
+
+	instance.Spec.GlanceAPIs["external"] = glancev1.GlanceAPITemplate{Type: "split"}
+	if err := r.ensureGlanceAPIs(ctx, instance); err != nil {
+		t.Fatalf("ensureGlanceAPIs() after Type change = %v", err)
+	}
+
+	if !containsString(instance.Status.LayoutMigrationsInProgress, "external") {
+		t.Fatalf("LayoutMigrationsInProgress = %v, want it to contain %q", instance.Status.LayoutMigrationsInProgress, "external")
+	}
+
+	glanceAPI := &glancev1.GlanceAPI{}
+	if err := r.Client.Get(ctx, types.NamespacedName{Name: "external", Namespace: "openstack"}, glanceAPI); err != nil {
+		t.Fatalf("Get(GlanceAPI) = %v", err)
+	}
+	if !glanceAPI.Spec.AllowLayoutMigration {
+		t.Errorf("GlanceAPI.Spec.AllowLayoutMigration = false, want true while migration is in progress")
+	}
+}
+
+func TestStepLayoutMigrationsCompletion(t *testing.T) {
+	scheme := newTestScheme(t)
+	instance := newTestGlance("glance", "openstack", map[string]glancev1.GlanceAPITemplate{
+		"external": {Type: "split"},
+	})
+	instance.Status.LayoutMigrationsInProgress = []string{"external"}
+
+	glanceAPI := &glancev1.GlanceAPI{
+		ObjectMeta: metav1.ObjectMeta{Name: "external", Namespace: "openstack"},
+		Spec:       glancev1.GlanceAPISpec{Type: "split", AllowLayoutMigration: true},
+	}
+
+	// The old "single" StatefulSet is already gone: ensureLayoutMigration
+	// treats that as migration-complete.
+	builder := fake.NewClientBuilder().WithScheme(scheme).WithObjects(instance, glanceAPI).WithStatusSubresource(instance)
+	r := &GlanceReconciler{Client: builder.Build(), Scheme: scheme}
+	ctx := context.Background()
+
+	result, err := r.stepLayoutMigrations(ctx, instance)
+	if err != nil {
+		t.Fatalf("stepLayoutMigrations() = %v", err)
+	}
+	if !result.IsZero() {
+		t.Fatalf("stepLayoutMigrations() result = %+v, want zero result once migration completes", result)
+	}
+	if len(instance.Status.LayoutMigrationsInProgress) != 0 {
+		t.Errorf("LayoutMigrationsInProgress = %v, want cleared", instance.Status.LayoutMigrationsInProgress)
+	}
+
+	got := &glancev1.GlanceAPI{}
+	if err := r.Client.Get(ctx, types.NamespacedName{Name: "external", Namespace: "openstack"}, got); err != nil {
+		t.Fatalf("Get(GlanceAPI) = %v", err)
+	}
+	if got.Spec.AllowLayoutMigration {
+		t.Errorf("GlanceAPI.Spec.AllowLayoutMigration = true, want reset to false once migration completes")
+	}
+}
@@ -0,0 +1,124 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	k8s_errors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	glancev1 "github.com/openstack-k8s-operators/glance-operator/api/v1beta1"
+)
+
+// layoutMigrationStatefulSetName - the StatefulSet name a given GlanceAPI
+// layout is rendered to. "single" collapses the whole backend into one
+// StatefulSet, "split" runs external/internal API instances separately.
+func layoutMigrationStatefulSetName(glanceAPIName string, layout string) string {
+	if layout == "split" {
+		return fmt.Sprintf("%s-split", glanceAPIName)
+	}
+	return glanceAPIName
+}
+
+// ensureLayoutMigration - performs one step of a supervised transition of a
+// GlanceAPI instance between the "single" and "split" deployment layouts.
+//
+// The transition is driven by scaling the new layout's StatefulSet up before
+// scaling the old one down, so the instance never drops below its current
+// Replicas count while requests drain from the StatefulSet being retired.
+// Callers are expected to requeue until the function reports the instance as
+// no longer migrating.
+func (r *GlanceReconciler) ensureLayoutMigration(
+	ctx context.Context,
+	instance *glancev1.Glance,
+	glanceAPIName string,
+	oldLayout string,
+	newLayout string,
+) (ctrl.Result, error) {
+	oldName := layoutMigrationStatefulSetName(glanceAPIName, oldLayout)
+	newName := layoutMigrationStatefulSetName(glanceAPIName, newLayout)
+
+	oldSS := &appsv1.StatefulSet{}
+	if err := r.Client.Get(ctx, types.NamespacedName{Name: oldName, Namespace: instance.Namespace}, oldSS); err != nil {
+		if !k8s_errors.IsNotFound(err) {
+			return ctrl.Result{}, err
+		}
+		// The old StatefulSet is already gone: the migration is complete.
+		return ctrl.Result{}, nil
+	}
+
+	newSS := &appsv1.StatefulSet{}
+	if err := r.Client.Get(ctx, types.NamespacedName{Name: newName, Namespace: instance.Namespace}, newSS); err != nil {
+		// The new layout hasn't been rendered by the regular reconcile loop
+		// yet: requeue and let it catch up first.
+		return ctrl.Result{RequeueAfter: layoutMigrationRequeueDelay}, nil
+	}
+
+	wantReplicas := replicasOrDefault(oldSS)
+
+	// Step 1: scale the new layout up to the full replica count and wait for
+	// it to report ready, so traffic has somewhere to go before we start
+	// draining the old one.
+	if replicasOrDefault(newSS) < wantReplicas {
+		newSS.Spec.Replicas = &wantReplicas
+		if err := r.Client.Update(ctx, newSS); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{RequeueAfter: layoutMigrationRequeueDelay}, nil
+	}
+	if newSS.Status.ReadyReplicas < wantReplicas {
+		return ctrl.Result{RequeueAfter: layoutMigrationRequeueDelay}, nil
+	}
+
+	// Step 2: drain and scale the old layout down now that the new one is
+	// serving. Scaling to zero rather than deleting keeps the PVCs around in
+	// case the migration needs to be rolled back.
+	if replicasOrDefault(oldSS) != 0 {
+		zero := int32(0)
+		oldSS.Spec.Replicas = &zero
+		if err := r.Client.Update(ctx, oldSS); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{RequeueAfter: layoutMigrationRequeueDelay}, nil
+	}
+
+	if oldSS.Status.Replicas != 0 {
+		// Still draining the last pods.
+		return ctrl.Result{RequeueAfter: layoutMigrationRequeueDelay}, nil
+	}
+
+	if err := r.Client.Delete(ctx, oldSS); err != nil && !k8s_errors.IsNotFound(err) {
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// replicasOrDefault - StatefulSet.Spec.Replicas is a pointer the apiserver
+// normally defaults to 1 on creation, but nothing in this package guarantees
+// that's happened by the time a fake/uninitialized StatefulSet is read here,
+// so treat nil the same way the apiserver would rather than panic.
+func replicasOrDefault(ss *appsv1.StatefulSet) int32 {
+	if ss.Spec.Replicas == nil {
+		return 1
+	}
+	return *ss.Spec.Replicas
+}
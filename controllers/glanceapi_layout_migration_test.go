@@ -0,0 +1,175 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	glancev1 "github.com/openstack-k8s-operators/glance-operator/api/v1beta1"
+)
+
+func int32Ptr(i int32) *int32 { return &i }
+
+func newMigrationStatefulSet(name, namespace string, replicas, readyReplicas, statusReplicas int32) *appsv1.StatefulSet {
+	return &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec:       appsv1.StatefulSetSpec{Replicas: int32Ptr(replicas)},
+		Status: appsv1.StatefulSetStatus{
+			ReadyReplicas: readyReplicas,
+			Replicas:      statusReplicas,
+		},
+	}
+}
+
+func TestEnsureLayoutMigrationWaitsForNewStatefulSetReady(t *testing.T) {
+	scheme := newTestScheme(t)
+	instance := newTestGlance("glance", "openstack", nil)
+
+	oldSS := newMigrationStatefulSet("external", "openstack", 2, 2, 2)
+	newSS := newMigrationStatefulSet("external-split", "openstack", 2, 1, 1)
+
+	r := &GlanceReconciler{
+		Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(oldSS, newSS).Build(),
+		Scheme: scheme,
+	}
+
+	result, err := r.ensureLayoutMigration(context.Background(), instance, "external", "single", "split")
+	if err != nil {
+		t.Fatalf("ensureLayoutMigration() = %v", err)
+	}
+	if result.IsZero() {
+		t.Fatalf("ensureLayoutMigration() result = %+v, want a requeue while the new StatefulSet isn't ready yet", result)
+	}
+
+	got := &appsv1.StatefulSet{}
+	if err := r.Client.Get(context.Background(), types.NamespacedName{Name: "external", Namespace: "openstack"}, got); err != nil {
+		t.Fatalf("Get(old StatefulSet) = %v", err)
+	}
+	if *got.Spec.Replicas != 2 {
+		t.Errorf("old StatefulSet Replicas = %d, want untouched at 2 while waiting for the new layout to become ready", *got.Spec.Replicas)
+	}
+}
+
+func TestEnsureLayoutMigrationScalesDownOldStatefulSet(t *testing.T) {
+	scheme := newTestScheme(t)
+	instance := newTestGlance("glance", "openstack", nil)
+
+	oldSS := newMigrationStatefulSet("external", "openstack", 2, 2, 2)
+	newSS := newMigrationStatefulSet("external-split", "openstack", 2, 2, 2)
+
+	r := &GlanceReconciler{
+		Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(oldSS, newSS).Build(),
+		Scheme: scheme,
+	}
+
+	result, err := r.ensureLayoutMigration(context.Background(), instance, "external", "single", "split")
+	if err != nil {
+		t.Fatalf("ensureLayoutMigration() = %v", err)
+	}
+	if result.IsZero() {
+		t.Fatalf("ensureLayoutMigration() result = %+v, want a requeue after kicking off the scale-down", result)
+	}
+
+	got := &appsv1.StatefulSet{}
+	if err := r.Client.Get(context.Background(), types.NamespacedName{Name: "external", Namespace: "openstack"}, got); err != nil {
+		t.Fatalf("Get(old StatefulSet) = %v", err)
+	}
+	if *got.Spec.Replicas != 0 {
+		t.Errorf("old StatefulSet Replicas = %d, want scaled to 0 now that the new layout is serving", *got.Spec.Replicas)
+	}
+}
+
+func TestEnsureLayoutMigrationWaitsForOldStatefulSetDrain(t *testing.T) {
+	scheme := newTestScheme(t)
+	instance := newTestGlance("glance", "openstack", nil)
+
+	// Spec already scaled to zero by a prior step, but pods are still
+	// terminating.
+	oldSS := newMigrationStatefulSet("external", "openstack", 0, 0, 1)
+	newSS := newMigrationStatefulSet("external-split", "openstack", 2, 2, 2)
+
+	r := &GlanceReconciler{
+		Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(oldSS, newSS).Build(),
+		Scheme: scheme,
+	}
+
+	result, err := r.ensureLayoutMigration(context.Background(), instance, "external", "single", "split")
+	if err != nil {
+		t.Fatalf("ensureLayoutMigration() = %v", err)
+	}
+	if result.IsZero() {
+		t.Fatalf("ensureLayoutMigration() result = %+v, want a requeue while the old StatefulSet is still draining", result)
+	}
+
+	got := &appsv1.StatefulSet{}
+	if err := r.Client.Get(context.Background(), types.NamespacedName{Name: "external", Namespace: "openstack"}, got); err != nil {
+		t.Fatalf("Get(old StatefulSet) = %v", err)
+	}
+}
+
+func TestEnsureLayoutMigrationCompletesByDeletingOldStatefulSet(t *testing.T) {
+	scheme := newTestScheme(t)
+	instance := newTestGlance("glance", "openstack", nil)
+
+	oldSS := newMigrationStatefulSet("external", "openstack", 0, 0, 0)
+	newSS := newMigrationStatefulSet("external-split", "openstack", 2, 2, 2)
+
+	r := &GlanceReconciler{
+		Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(oldSS, newSS).Build(),
+		Scheme: scheme,
+	}
+
+	result, err := r.ensureLayoutMigration(context.Background(), instance, "external", "single", "split")
+	if err != nil {
+		t.Fatalf("ensureLayoutMigration() = %v", err)
+	}
+	if !result.IsZero() {
+		t.Fatalf("ensureLayoutMigration() result = %+v, want zero result once the old StatefulSet is fully drained", result)
+	}
+
+	err = r.Client.Get(context.Background(), types.NamespacedName{Name: "external", Namespace: "openstack"}, &appsv1.StatefulSet{})
+	if err == nil {
+		t.Errorf("old StatefulSet %q still exists, want it deleted once the migration completes", "external")
+	}
+}
+
+func TestReplicasOrDefault(t *testing.T) {
+	tests := []struct {
+		name     string
+		replicas *int32
+		want     int32
+	}{
+		{name: "nil Replicas defaults to 1", replicas: nil, want: 1},
+		{name: "explicit zero is respected", replicas: int32Ptr(0), want: 0},
+		{name: "explicit value is respected", replicas: int32Ptr(3), want: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ss := &appsv1.StatefulSet{Spec: appsv1.StatefulSetSpec{Replicas: tt.replicas}}
+			if got := replicasOrDefault(ss); got != tt.want {
+				t.Errorf("replicasOrDefault() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}